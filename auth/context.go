@@ -0,0 +1,20 @@
+package auth
+
+import "context"
+
+type contextKey string
+
+const userIDContextKey contextKey = "userID"
+
+// ContextWithUserID returns a copy of ctx carrying the authenticated user's
+// id, as set by the middleware that validated their access token.
+func ContextWithUserID(ctx context.Context, userID int64) context.Context {
+	return context.WithValue(ctx, userIDContextKey, userID)
+}
+
+// UserIDFromContext returns the authenticated user id stored by
+// ContextWithUserID, and whether one was present.
+func UserIDFromContext(ctx context.Context) (int64, bool) {
+	userID, ok := ctx.Value(userIDContextKey).(int64)
+	return userID, ok
+}
@@ -0,0 +1,22 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// ErrMissingBearerToken is returned when an Authorization header is absent
+// or is not a well-formed "Bearer <token>" value.
+var ErrMissingBearerToken = errors.New("missing or malformed Authorization header")
+
+// BearerToken extracts the token from a request's "Authorization: Bearer
+// <token>" header.
+func BearerToken(r *http.Request) (string, error) {
+	header := r.Header.Get("Authorization")
+	parts := strings.SplitN(header, " ", 2)
+	if len(parts) != 2 || parts[0] != "Bearer" || parts[1] == "" {
+		return "", ErrMissingBearerToken
+	}
+	return parts[1], nil
+}
@@ -0,0 +1,82 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Issuers distinguish access tokens from refresh tokens so one can never be
+// presented in place of the other, even though both are HS256 JWTs signed
+// with the same secret.
+const (
+	AccessTokenIssuer  = "chirpy-access"
+	RefreshTokenIssuer = "chirpy-refresh"
+)
+
+// ErrInvalidToken covers any failure to parse, verify, or recognize a JWT:
+// bad signature, wrong issuer, expired, or malformed subject.
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// MakeAccessJWT issues a short-lived access token for userID.
+func MakeAccessJWT(userID int64, secret string, expiresIn time.Duration) (string, error) {
+	return makeJWT(userID, secret, AccessTokenIssuer, expiresIn)
+}
+
+// MakeRefreshJWT issues a long-lived refresh token for userID. Callers are
+// expected to persist it via a database.RefreshTokenStore so it can be
+// looked up and revoked later.
+func MakeRefreshJWT(userID int64, secret string, expiresIn time.Duration) (string, error) {
+	return makeJWT(userID, secret, RefreshTokenIssuer, expiresIn)
+}
+
+func makeJWT(userID int64, secret, issuer string, expiresIn time.Duration) (string, error) {
+	now := time.Now().UTC()
+	claims := jwt.RegisteredClaims{
+		Issuer:    issuer,
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(expiresIn)),
+		Subject:   strconv.FormatInt(userID, 10),
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(secret))
+}
+
+// ValidateAccessJWT verifies an access token's signature, issuer, and
+// expiry, returning the user id it was issued for.
+func ValidateAccessJWT(tokenString, secret string) (int64, error) {
+	return validateJWT(tokenString, secret, AccessTokenIssuer)
+}
+
+// ValidateRefreshJWT verifies a refresh token's signature, issuer, and
+// expiry. Callers still need to check the database.RefreshTokenStore for
+// revocation, since a valid-looking JWT can have been revoked server-side.
+func ValidateRefreshJWT(tokenString, secret string) (int64, error) {
+	return validateJWT(tokenString, secret, RefreshTokenIssuer)
+}
+
+func validateJWT(tokenString, secret, wantIssuer string) (int64, error) {
+	claims := &jwt.RegisteredClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		return []byte(secret), nil
+	})
+	if err != nil || !token.Valid {
+		return 0, ErrInvalidToken
+	}
+
+	if claims.Issuer != wantIssuer {
+		return 0, ErrInvalidToken
+	}
+
+	userID, err := strconv.ParseInt(claims.Subject, 10, 64)
+	if err != nil {
+		return 0, ErrInvalidToken
+	}
+	return userID, nil
+}
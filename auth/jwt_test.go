@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMakeAndValidateAccessJWT(t *testing.T) {
+	token, err := MakeAccessJWT(42, "secret", time.Hour)
+	if err != nil {
+		t.Fatalf("MakeAccessJWT: %s", err)
+	}
+
+	userID, err := ValidateAccessJWT(token, "secret")
+	if err != nil {
+		t.Fatalf("ValidateAccessJWT: %s", err)
+	}
+	if userID != 42 {
+		t.Errorf("got userID %d, want 42", userID)
+	}
+}
+
+func TestValidateAccessJWTWrongSecret(t *testing.T) {
+	token, err := MakeAccessJWT(42, "secret", time.Hour)
+	if err != nil {
+		t.Fatalf("MakeAccessJWT: %s", err)
+	}
+
+	if _, err := ValidateAccessJWT(token, "wrong-secret"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("got err %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestValidateAccessJWTExpired(t *testing.T) {
+	token, err := MakeAccessJWT(42, "secret", -time.Hour)
+	if err != nil {
+		t.Fatalf("MakeAccessJWT: %s", err)
+	}
+
+	if _, err := ValidateAccessJWT(token, "secret"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("got err %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestAccessAndRefreshTokensAreNotInterchangeable(t *testing.T) {
+	refreshToken, err := MakeRefreshJWT(42, "secret", time.Hour)
+	if err != nil {
+		t.Fatalf("MakeRefreshJWT: %s", err)
+	}
+
+	if _, err := ValidateAccessJWT(refreshToken, "secret"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("got err %v, want ErrInvalidToken when validating a refresh token as an access token", err)
+	}
+
+	accessToken, err := MakeAccessJWT(42, "secret", time.Hour)
+	if err != nil {
+		t.Fatalf("MakeAccessJWT: %s", err)
+	}
+	if _, err := ValidateRefreshJWT(accessToken, "secret"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("got err %v, want ErrInvalidToken when validating an access token as a refresh token", err)
+	}
+}
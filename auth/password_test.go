@@ -0,0 +1,18 @@
+package auth
+
+import "testing"
+
+func TestHashAndCheckPassword(t *testing.T) {
+	hash, err := HashPassword("correct-horse-battery-staple")
+	if err != nil {
+		t.Fatalf("HashPassword: %s", err)
+	}
+
+	if err := CheckPasswordHash("correct-horse-battery-staple", hash); err != nil {
+		t.Errorf("CheckPasswordHash: %s", err)
+	}
+
+	if err := CheckPasswordHash("wrong-password", hash); err == nil {
+		t.Errorf("expected an error for a wrong password")
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/miketeow/gowebserver/auth"
+)
+
+func TestUsersCreateHandler(t *testing.T) {
+	cfg := newTestAPIConfig()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/users", bytes.NewBufferString(
+		`{"email": "alice@example.com", "password": "hunter2"}`))
+	rec := httptest.NewRecorder()
+
+	cfg.usersCreateHandler(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d (body: %s)", rec.Code, http.StatusCreated, rec.Body.String())
+	}
+
+	// Registering the same email again should fail.
+	req = httptest.NewRequest(http.MethodPost, "/api/users", bytes.NewBufferString(
+		`{"email": "alice@example.com", "password": "hunter2"}`))
+	rec = httptest.NewRecorder()
+	cfg.usersCreateHandler(rec, req)
+
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusConflict)
+	}
+}
+
+func TestLoginRefreshRevokeFlow(t *testing.T) {
+	cfg := newTestAPIConfig()
+
+	registerReq := httptest.NewRequest(http.MethodPost, "/api/users", bytes.NewBufferString(
+		`{"email": "bob@example.com", "password": "hunter2"}`))
+	registerRec := httptest.NewRecorder()
+	cfg.usersCreateHandler(registerRec, registerReq)
+	if registerRec.Code != http.StatusCreated {
+		t.Fatalf("setup: register status = %d (body: %s)", registerRec.Code, registerRec.Body.String())
+	}
+
+	loginReq := httptest.NewRequest(http.MethodPost, "/api/login", bytes.NewBufferString(
+		`{"email": "bob@example.com", "password": "wrong-password"}`))
+	loginRec := httptest.NewRecorder()
+	cfg.loginHandler(loginRec, loginReq)
+	if loginRec.Code != http.StatusUnauthorized {
+		t.Fatalf("login with wrong password: status = %d, want %d", loginRec.Code, http.StatusUnauthorized)
+	}
+
+	loginReq = httptest.NewRequest(http.MethodPost, "/api/login", bytes.NewBufferString(
+		`{"email": "bob@example.com", "password": "hunter2"}`))
+	loginRec = httptest.NewRecorder()
+	cfg.loginHandler(loginRec, loginReq)
+	if loginRec.Code != http.StatusOK {
+		t.Fatalf("login status = %d, want %d (body: %s)", loginRec.Code, http.StatusOK, loginRec.Body.String())
+	}
+
+	var loginResp struct {
+		Token        string `json:"token"`
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := json.Unmarshal(loginRec.Body.Bytes(), &loginResp); err != nil {
+		t.Fatalf("failed to decode login response: %s", err)
+	}
+	if loginResp.Token == "" || loginResp.RefreshToken == "" {
+		t.Fatalf("expected both an access token and a refresh token, got %+v", loginResp)
+	}
+
+	refreshReq := httptest.NewRequest(http.MethodPost, "/api/refresh", nil)
+	refreshReq.Header.Set("Authorization", "Bearer "+loginResp.RefreshToken)
+	refreshRec := httptest.NewRecorder()
+	cfg.refreshHandler(refreshRec, refreshReq)
+	if refreshRec.Code != http.StatusOK {
+		t.Fatalf("refresh status = %d, want %d (body: %s)", refreshRec.Code, http.StatusOK, refreshRec.Body.String())
+	}
+
+	revokeReq := httptest.NewRequest(http.MethodPost, "/api/revoke", nil)
+	revokeReq.Header.Set("Authorization", "Bearer "+loginResp.RefreshToken)
+	revokeRec := httptest.NewRecorder()
+	cfg.revokeHandler(revokeRec, revokeReq)
+	if revokeRec.Code != http.StatusNoContent {
+		t.Fatalf("revoke status = %d, want %d", revokeRec.Code, http.StatusNoContent)
+	}
+
+	refreshReq = httptest.NewRequest(http.MethodPost, "/api/refresh", nil)
+	refreshReq.Header.Set("Authorization", "Bearer "+loginResp.RefreshToken)
+	refreshRec = httptest.NewRecorder()
+	cfg.refreshHandler(refreshRec, refreshReq)
+	if refreshRec.Code != http.StatusUnauthorized {
+		t.Fatalf("refresh after revoke: status = %d, want %d", refreshRec.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	cfg := newTestAPIConfig()
+	protected := cfg.authMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	t.Run("missing header", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+		rec := httptest.NewRecorder()
+		protected.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+		}
+	})
+
+	t.Run("valid token", func(t *testing.T) {
+		token, err := auth.MakeAccessJWT(1, cfg.jwtSecret, time.Hour)
+		if err != nil {
+			t.Fatalf("failed to create access token: %s", err)
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		protected.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+		}
+	})
+}
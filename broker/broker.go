@@ -0,0 +1,23 @@
+// Package broker fans newly created chirps out to live subscribers, such as
+// the SSE stream handler.
+package broker
+
+import "github.com/miketeow/gowebserver/database"
+
+// Broker publishes chirps to subscribers. It's defined as an interface so
+// the in-process implementation here can later be swapped for one backed
+// by Redis pub/sub without changing any handler code.
+type Broker interface {
+	// Publish fans chirp out to every current subscriber. It must not
+	// block on a slow or stalled subscriber.
+	Publish(chirp database.Chirp)
+	// Subscribe registers a new subscriber, returning a channel of chirps
+	// and an unsubscribe function that releases it. The channel is closed
+	// once unsubscribe is called or the broker is closed.
+	Subscribe() (ch <-chan database.Chirp, unsubscribe func())
+	// Ready reports whether the broker can currently accept subscribers
+	// and publishes.
+	Ready() bool
+	// Close shuts the broker down, closing every subscriber channel.
+	Close()
+}
@@ -0,0 +1,92 @@
+package broker
+
+import (
+	"sync"
+
+	"github.com/miketeow/gowebserver/database"
+)
+
+// DefaultBufferSize is the per-subscriber channel buffer used when
+// NewInProcessBroker is called with bufferSize <= 0.
+const DefaultBufferSize = 16
+
+// InProcessBroker is a Broker that fans chirps out to in-memory channels.
+// A subscriber that falls behind has new chirps dropped rather than
+// blocking publishers, since a live feed has no use for stale backlog.
+type InProcessBroker struct {
+	mu          sync.Mutex
+	bufferSize  int
+	subscribers map[chan database.Chirp]struct{}
+	closed      bool
+}
+
+// NewInProcessBroker returns a ready-to-use in-process broker. bufferSize
+// is the number of chirps buffered per subscriber before the slow-consumer
+// drop policy kicks in; DefaultBufferSize is used if bufferSize <= 0.
+func NewInProcessBroker(bufferSize int) *InProcessBroker {
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+	return &InProcessBroker{
+		bufferSize:  bufferSize,
+		subscribers: make(map[chan database.Chirp]struct{}),
+	}
+}
+
+func (b *InProcessBroker) Publish(chirp database.Chirp) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- chirp:
+		default:
+			// Slow consumer: drop the chirp rather than block the publisher.
+		}
+	}
+}
+
+func (b *InProcessBroker) Subscribe() (<-chan database.Chirp, func()) {
+	ch := make(chan database.Chirp, b.bufferSize)
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		close(ch)
+		return ch, func() {}
+	}
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			if _, ok := b.subscribers[ch]; ok {
+				delete(b.subscribers, ch)
+				close(ch)
+			}
+		})
+	}
+	return ch, unsubscribe
+}
+
+func (b *InProcessBroker) Ready() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return !b.closed
+}
+
+func (b *InProcessBroker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	b.closed = true
+	for ch := range b.subscribers {
+		close(ch)
+	}
+	b.subscribers = nil
+}
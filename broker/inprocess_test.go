@@ -0,0 +1,70 @@
+package broker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/miketeow/gowebserver/database"
+)
+
+func TestInProcessBrokerPublishSubscribe(t *testing.T) {
+	b := NewInProcessBroker(1)
+	ch, unsubscribe := b.Subscribe()
+	defer unsubscribe()
+
+	chirp := database.Chirp{ID: 1, Body: "hello"}
+	b.Publish(chirp)
+
+	select {
+	case got := <-ch:
+		if got != chirp {
+			t.Errorf("got %+v, want %+v", got, chirp)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for published chirp")
+	}
+}
+
+func TestInProcessBrokerSlowConsumerDropsRatherThanBlocks(t *testing.T) {
+	b := NewInProcessBroker(1)
+	_, unsubscribe := b.Subscribe()
+	defer unsubscribe()
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 10; i++ {
+			b.Publish(database.Chirp{ID: int64(i)})
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Publish blocked on a full subscriber channel instead of dropping")
+	}
+}
+
+func TestInProcessBrokerUnsubscribeClosesChannel(t *testing.T) {
+	b := NewInProcessBroker(1)
+	ch, unsubscribe := b.Subscribe()
+	unsubscribe()
+
+	if _, ok := <-ch; ok {
+		t.Error("expected channel to be closed after unsubscribe")
+	}
+}
+
+func TestInProcessBrokerCloseStopsAcceptingSubscribers(t *testing.T) {
+	b := NewInProcessBroker(1)
+	b.Close()
+
+	if b.Ready() {
+		t.Error("expected Ready() to be false after Close()")
+	}
+
+	ch, _ := b.Subscribe()
+	if _, ok := <-ch; ok {
+		t.Error("expected a channel from Subscribe after Close to be already closed")
+	}
+}
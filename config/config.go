@@ -0,0 +1,103 @@
+// Package config loads and validates the server's runtime configuration
+// from environment variables.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Defaults applied to any setting left unset in the environment.
+const (
+	DefaultAddr                = ":8080"
+	DefaultReadHeaderTimeout   = 5 * time.Second
+	DefaultReadTimeout         = 10 * time.Second
+	DefaultWriteTimeout        = 10 * time.Second
+	DefaultIdleTimeout         = 120 * time.Second
+	DefaultShutdownGracePeriod = 10 * time.Second
+)
+
+// ErrMissingJWTSecret is returned by Load when the JWT_SECRET environment
+// variable is unset or empty.
+var ErrMissingJWTSecret = errors.New("JWT_SECRET environment variable must be set")
+
+// ErrMissingDatabaseURL is returned by Load when the DATABASE_URL
+// environment variable is unset or empty.
+var ErrMissingDatabaseURL = errors.New("DATABASE_URL environment variable must be set")
+
+// Config holds the server's runtime configuration.
+type Config struct {
+	Addr        string
+	JWTSecret   string
+	DatabaseURL string
+
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+
+	ShutdownGracePeriod time.Duration
+}
+
+// Load reads configuration from the environment, applying defaults for
+// anything unset, and validates the result.
+func Load() (*Config, error) {
+	cfg := &Config{
+		Addr:        envOr("ADDR", DefaultAddr),
+		JWTSecret:   os.Getenv("JWT_SECRET"),
+		DatabaseURL: os.Getenv("DATABASE_URL"),
+	}
+
+	var err error
+	if cfg.ReadHeaderTimeout, err = durationOr("READ_HEADER_TIMEOUT", DefaultReadHeaderTimeout); err != nil {
+		return nil, err
+	}
+	if cfg.ReadTimeout, err = durationOr("READ_TIMEOUT", DefaultReadTimeout); err != nil {
+		return nil, err
+	}
+	if cfg.WriteTimeout, err = durationOr("WRITE_TIMEOUT", DefaultWriteTimeout); err != nil {
+		return nil, err
+	}
+	if cfg.IdleTimeout, err = durationOr("IDLE_TIMEOUT", DefaultIdleTimeout); err != nil {
+		return nil, err
+	}
+	if cfg.ShutdownGracePeriod, err = durationOr("SHUTDOWN_GRACE_PERIOD", DefaultShutdownGracePeriod); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (c *Config) validate() error {
+	if c.JWTSecret == "" {
+		return ErrMissingJWTSecret
+	}
+	if c.DatabaseURL == "" {
+		return ErrMissingDatabaseURL
+	}
+	return nil
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func durationOr(key string, fallback time.Duration) (time.Duration, error) {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return d, nil
+}
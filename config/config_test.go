@@ -0,0 +1,76 @@
+package config
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLoadAppliesDefaults(t *testing.T) {
+	t.Setenv("JWT_SECRET", "secret")
+	t.Setenv("DATABASE_URL", "postgres://localhost/chirpy")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %s", err)
+	}
+
+	if cfg.Addr != DefaultAddr {
+		t.Errorf("Addr = %q, want %q", cfg.Addr, DefaultAddr)
+	}
+	if cfg.ReadHeaderTimeout != DefaultReadHeaderTimeout {
+		t.Errorf("ReadHeaderTimeout = %s, want %s", cfg.ReadHeaderTimeout, DefaultReadHeaderTimeout)
+	}
+	if cfg.ShutdownGracePeriod != DefaultShutdownGracePeriod {
+		t.Errorf("ShutdownGracePeriod = %s, want %s", cfg.ShutdownGracePeriod, DefaultShutdownGracePeriod)
+	}
+}
+
+func TestLoadReadsOverrides(t *testing.T) {
+	t.Setenv("JWT_SECRET", "secret")
+	t.Setenv("DATABASE_URL", "postgres://localhost/chirpy")
+	t.Setenv("ADDR", ":9090")
+	t.Setenv("WRITE_TIMEOUT", "2s")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %s", err)
+	}
+
+	if cfg.Addr != ":9090" {
+		t.Errorf("Addr = %q, want :9090", cfg.Addr)
+	}
+	if cfg.WriteTimeout != 2*time.Second {
+		t.Errorf("WriteTimeout = %s, want 2s", cfg.WriteTimeout)
+	}
+}
+
+func TestLoadRejectsMissingJWTSecret(t *testing.T) {
+	t.Setenv("JWT_SECRET", "")
+	t.Setenv("DATABASE_URL", "postgres://localhost/chirpy")
+
+	_, err := Load()
+	if !errors.Is(err, ErrMissingJWTSecret) {
+		t.Fatalf("err = %v, want ErrMissingJWTSecret", err)
+	}
+}
+
+func TestLoadRejectsMissingDatabaseURL(t *testing.T) {
+	t.Setenv("JWT_SECRET", "secret")
+	t.Setenv("DATABASE_URL", "")
+
+	_, err := Load()
+	if !errors.Is(err, ErrMissingDatabaseURL) {
+		t.Fatalf("err = %v, want ErrMissingDatabaseURL", err)
+	}
+}
+
+func TestLoadRejectsInvalidDuration(t *testing.T) {
+	t.Setenv("JWT_SECRET", "secret")
+	t.Setenv("DATABASE_URL", "postgres://localhost/chirpy")
+	t.Setenv("IDLE_TIMEOUT", "not-a-duration")
+
+	if _, err := Load(); err == nil {
+		t.Fatal("expected an error for an invalid IDLE_TIMEOUT")
+	}
+}
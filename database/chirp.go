@@ -0,0 +1,50 @@
+// Package database holds the persistence layer for Chirpy: the Chirp model
+// and the ChirpStore interface, along with its implementations.
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Chirp is a single posted chirp.
+type Chirp struct {
+	ID        int64     `json:"id"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+	AuthorID  int64     `json:"author_id"`
+}
+
+// ErrChirpNotFound is returned when a chirp id does not exist in the store.
+var ErrChirpNotFound = errors.New("chirp not found")
+
+// ErrNotChirpOwner is returned when the caller tries to delete a chirp they
+// do not own.
+var ErrNotChirpOwner = errors.New("caller is not the owner of this chirp")
+
+// SortOrder controls the ordering of ListChirps results by created_at.
+type SortOrder string
+
+const (
+	SortAsc  SortOrder = "asc"
+	SortDesc SortOrder = "desc"
+)
+
+// ListChirpsParams filters and orders the result of ListChirps. A zero
+// AuthorID means "all authors".
+type ListChirpsParams struct {
+	AuthorID int64
+	Sort     SortOrder
+}
+
+// ChirpStore persists and retrieves chirps. Implementations must be safe for
+// concurrent use.
+type ChirpStore interface {
+	CreateChirp(ctx context.Context, body string, authorID int64) (Chirp, error)
+	ListChirps(ctx context.Context, params ListChirpsParams) ([]Chirp, error)
+	GetChirp(ctx context.Context, id int64) (Chirp, error)
+	// DeleteChirp deletes the chirp if it exists and is owned by authorID.
+	// It returns ErrChirpNotFound or ErrNotChirpOwner otherwise.
+	DeleteChirp(ctx context.Context, id int64, authorID int64) error
+}
@@ -0,0 +1,91 @@
+package database
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryChirpStore is an in-memory ChirpStore, primarily intended for tests.
+type MemoryChirpStore struct {
+	mu     sync.Mutex
+	nextID int64
+	chirps map[int64]Chirp
+}
+
+// NewMemoryChirpStore returns an empty in-memory chirp store.
+func NewMemoryChirpStore() *MemoryChirpStore {
+	return &MemoryChirpStore{
+		nextID: 1,
+		chirps: make(map[int64]Chirp),
+	}
+}
+
+// Ping always succeeds: an in-memory store has no external dependency to
+// check.
+func (s *MemoryChirpStore) Ping(_ context.Context) error {
+	return nil
+}
+
+func (s *MemoryChirpStore) CreateChirp(_ context.Context, body string, authorID int64) (Chirp, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	chirp := Chirp{
+		ID:        s.nextID,
+		Body:      body,
+		CreatedAt: time.Now().UTC(),
+		AuthorID:  authorID,
+	}
+	s.chirps[chirp.ID] = chirp
+	s.nextID++
+	return chirp, nil
+}
+
+func (s *MemoryChirpStore) ListChirps(_ context.Context, params ListChirpsParams) ([]Chirp, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	chirps := make([]Chirp, 0, len(s.chirps))
+	for _, chirp := range s.chirps {
+		if params.AuthorID != 0 && chirp.AuthorID != params.AuthorID {
+			continue
+		}
+		chirps = append(chirps, chirp)
+	}
+
+	sort.Slice(chirps, func(i, j int) bool {
+		if params.Sort == SortDesc {
+			return chirps[i].CreatedAt.After(chirps[j].CreatedAt)
+		}
+		return chirps[i].CreatedAt.Before(chirps[j].CreatedAt)
+	})
+	return chirps, nil
+}
+
+func (s *MemoryChirpStore) GetChirp(_ context.Context, id int64) (Chirp, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	chirp, ok := s.chirps[id]
+	if !ok {
+		return Chirp{}, ErrChirpNotFound
+	}
+	return chirp, nil
+}
+
+func (s *MemoryChirpStore) DeleteChirp(_ context.Context, id int64, authorID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	chirp, ok := s.chirps[id]
+	if !ok {
+		return ErrChirpNotFound
+	}
+	if chirp.AuthorID != authorID {
+		return ErrNotChirpOwner
+	}
+	delete(s.chirps, id)
+	return nil
+}
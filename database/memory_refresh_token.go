@@ -0,0 +1,57 @@
+package database
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryRefreshTokenStore is an in-memory RefreshTokenStore, primarily
+// intended for tests.
+type MemoryRefreshTokenStore struct {
+	mu     sync.Mutex
+	tokens map[string]RefreshToken
+}
+
+// NewMemoryRefreshTokenStore returns an empty in-memory refresh token store.
+func NewMemoryRefreshTokenStore() *MemoryRefreshTokenStore {
+	return &MemoryRefreshTokenStore{tokens: make(map[string]RefreshToken)}
+}
+
+func (s *MemoryRefreshTokenStore) CreateRefreshToken(_ context.Context, token string, userID int64, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tokens[token] = RefreshToken{
+		Token:     token,
+		UserID:    userID,
+		CreatedAt: time.Now().UTC(),
+		ExpiresAt: expiresAt,
+	}
+	return nil
+}
+
+func (s *MemoryRefreshTokenStore) GetRefreshToken(_ context.Context, token string) (RefreshToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rt, ok := s.tokens[token]
+	if !ok {
+		return RefreshToken{}, ErrRefreshTokenNotFound
+	}
+	return rt, nil
+}
+
+func (s *MemoryRefreshTokenStore) RevokeRefreshToken(_ context.Context, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rt, ok := s.tokens[token]
+	if !ok {
+		return ErrRefreshTokenNotFound
+	}
+	now := time.Now().UTC()
+	rt.RevokedAt = &now
+	s.tokens[token] = rt
+	return nil
+}
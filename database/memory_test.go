@@ -0,0 +1,73 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestMemoryChirpStoreCreateAndGet(t *testing.T) {
+	store := NewMemoryChirpStore()
+	ctx := context.Background()
+
+	chirp, err := store.CreateChirp(ctx, "hello", 1)
+	if err != nil {
+		t.Fatalf("CreateChirp: %s", err)
+	}
+
+	got, err := store.GetChirp(ctx, chirp.ID)
+	if err != nil {
+		t.Fatalf("GetChirp: %s", err)
+	}
+	if got != chirp {
+		t.Errorf("got %+v, want %+v", got, chirp)
+	}
+}
+
+func TestMemoryChirpStoreGetNotFound(t *testing.T) {
+	store := NewMemoryChirpStore()
+
+	_, err := store.GetChirp(context.Background(), 999)
+	if !errors.Is(err, ErrChirpNotFound) {
+		t.Errorf("got err %v, want ErrChirpNotFound", err)
+	}
+}
+
+func TestMemoryChirpStoreListFiltersAndSorts(t *testing.T) {
+	store := NewMemoryChirpStore()
+	ctx := context.Background()
+
+	first, _ := store.CreateChirp(ctx, "first", 1)
+	second, _ := store.CreateChirp(ctx, "second", 1)
+	_, _ = store.CreateChirp(ctx, "other author", 2)
+
+	chirps, err := store.ListChirps(ctx, ListChirpsParams{AuthorID: 1, Sort: SortDesc})
+	if err != nil {
+		t.Fatalf("ListChirps: %s", err)
+	}
+	if len(chirps) != 2 {
+		t.Fatalf("got %d chirps, want 2", len(chirps))
+	}
+	if chirps[0].ID != second.ID || chirps[1].ID != first.ID {
+		t.Errorf("got order %+v, want descending by created_at", chirps)
+	}
+}
+
+func TestMemoryChirpStoreDelete(t *testing.T) {
+	store := NewMemoryChirpStore()
+	ctx := context.Background()
+
+	chirp, _ := store.CreateChirp(ctx, "hello", 1)
+
+	if err := store.DeleteChirp(ctx, chirp.ID, 2); !errors.Is(err, ErrNotChirpOwner) {
+		t.Errorf("got err %v, want ErrNotChirpOwner", err)
+	}
+
+	if err := store.DeleteChirp(ctx, chirp.ID, 1); err != nil {
+		t.Fatalf("DeleteChirp: %s", err)
+	}
+
+	if _, err := store.GetChirp(ctx, chirp.ID); !errors.Is(err, ErrChirpNotFound) {
+		t.Errorf("got err %v, want ErrChirpNotFound after delete", err)
+	}
+}
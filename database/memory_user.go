@@ -0,0 +1,66 @@
+package database
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryUserStore is an in-memory UserStore, primarily intended for tests.
+type MemoryUserStore struct {
+	mu         sync.Mutex
+	nextID     int64
+	users      map[int64]User
+	emailIndex map[string]int64
+}
+
+// NewMemoryUserStore returns an empty in-memory user store.
+func NewMemoryUserStore() *MemoryUserStore {
+	return &MemoryUserStore{
+		nextID:     1,
+		users:      make(map[int64]User),
+		emailIndex: make(map[string]int64),
+	}
+}
+
+func (s *MemoryUserStore) CreateUser(_ context.Context, email, hashedPassword string) (User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, taken := s.emailIndex[email]; taken {
+		return User{}, ErrEmailTaken
+	}
+
+	user := User{
+		ID:             s.nextID,
+		Email:          email,
+		HashedPassword: hashedPassword,
+		CreatedAt:      time.Now().UTC(),
+	}
+	s.users[user.ID] = user
+	s.emailIndex[email] = user.ID
+	s.nextID++
+	return user, nil
+}
+
+func (s *MemoryUserStore) GetUserByEmail(_ context.Context, email string) (User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, ok := s.emailIndex[email]
+	if !ok {
+		return User{}, ErrUserNotFound
+	}
+	return s.users[id], nil
+}
+
+func (s *MemoryUserStore) GetUserByID(_ context.Context, id int64) (User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[id]
+	if !ok {
+		return User{}, ErrUserNotFound
+	}
+	return user, nil
+}
@@ -0,0 +1,9 @@
+package database
+
+import "context"
+
+// Pinger is implemented by stores that can verify their underlying
+// dependency is reachable. Handlers use it for readiness checks.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
@@ -0,0 +1,106 @@
+package database
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresChirpStore is a ChirpStore backed by a PostgreSQL connection pool.
+type PostgresChirpStore struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresChirpStore wraps an existing pgx pool. The caller owns the
+// pool's lifecycle and must close it on shutdown.
+func NewPostgresChirpStore(pool *pgxpool.Pool) *PostgresChirpStore {
+	return &PostgresChirpStore{pool: pool}
+}
+
+// Ping verifies the underlying connection pool can reach the database.
+func (s *PostgresChirpStore) Ping(ctx context.Context) error {
+	return s.pool.Ping(ctx)
+}
+
+func (s *PostgresChirpStore) CreateChirp(ctx context.Context, body string, authorID int64) (Chirp, error) {
+	const query = `
+		INSERT INTO chirps (body, author_id)
+		VALUES ($1, $2)
+		RETURNING id, body, created_at, author_id
+	`
+	var chirp Chirp
+	err := s.pool.QueryRow(ctx, query, body, authorID).Scan(
+		&chirp.ID, &chirp.Body, &chirp.CreatedAt, &chirp.AuthorID,
+	)
+	if err != nil {
+		return Chirp{}, err
+	}
+	return chirp, nil
+}
+
+func (s *PostgresChirpStore) ListChirps(ctx context.Context, params ListChirpsParams) ([]Chirp, error) {
+	order := "ASC"
+	if params.Sort == SortDesc {
+		order = "DESC"
+	}
+
+	query := `
+		SELECT id, body, created_at, author_id
+		FROM chirps
+		WHERE ($1 = 0 OR author_id = $1)
+		ORDER BY created_at ` + order
+
+	rows, err := s.pool.Query(ctx, query, params.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var chirps []Chirp
+	for rows.Next() {
+		var chirp Chirp
+		if err := rows.Scan(&chirp.ID, &chirp.Body, &chirp.CreatedAt, &chirp.AuthorID); err != nil {
+			return nil, err
+		}
+		chirps = append(chirps, chirp)
+	}
+	return chirps, rows.Err()
+}
+
+func (s *PostgresChirpStore) GetChirp(ctx context.Context, id int64) (Chirp, error) {
+	const query = `
+		SELECT id, body, created_at, author_id
+		FROM chirps
+		WHERE id = $1
+	`
+	var chirp Chirp
+	err := s.pool.QueryRow(ctx, query, id).Scan(
+		&chirp.ID, &chirp.Body, &chirp.CreatedAt, &chirp.AuthorID,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Chirp{}, ErrChirpNotFound
+	}
+	if err != nil {
+		return Chirp{}, err
+	}
+	return chirp, nil
+}
+
+func (s *PostgresChirpStore) DeleteChirp(ctx context.Context, id int64, authorID int64) error {
+	const query = `DELETE FROM chirps WHERE id = $1 AND author_id = $2`
+	tag, err := s.pool.Exec(ctx, query, id, authorID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		// Distinguish "doesn't exist" from "not yours" for a clearer error.
+		_, err := s.GetChirp(ctx, id)
+		if errors.Is(err, ErrChirpNotFound) {
+			return ErrChirpNotFound
+		}
+		return ErrNotChirpOwner
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresRefreshTokenStore is a RefreshTokenStore backed by a PostgreSQL
+// connection pool.
+type PostgresRefreshTokenStore struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRefreshTokenStore wraps an existing pgx pool. The caller owns
+// the pool's lifecycle and must close it on shutdown.
+func NewPostgresRefreshTokenStore(pool *pgxpool.Pool) *PostgresRefreshTokenStore {
+	return &PostgresRefreshTokenStore{pool: pool}
+}
+
+func (s *PostgresRefreshTokenStore) CreateRefreshToken(ctx context.Context, token string, userID int64, expiresAt time.Time) error {
+	const query = `
+		INSERT INTO refresh_tokens (token, user_id, expires_at)
+		VALUES ($1, $2, $3)
+	`
+	_, err := s.pool.Exec(ctx, query, token, userID, expiresAt)
+	return err
+}
+
+func (s *PostgresRefreshTokenStore) GetRefreshToken(ctx context.Context, token string) (RefreshToken, error) {
+	const query = `
+		SELECT token, user_id, created_at, expires_at, revoked_at
+		FROM refresh_tokens
+		WHERE token = $1
+	`
+	var rt RefreshToken
+	err := s.pool.QueryRow(ctx, query, token).Scan(
+		&rt.Token, &rt.UserID, &rt.CreatedAt, &rt.ExpiresAt, &rt.RevokedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return RefreshToken{}, ErrRefreshTokenNotFound
+	}
+	if err != nil {
+		return RefreshToken{}, err
+	}
+	return rt, nil
+}
+
+func (s *PostgresRefreshTokenStore) RevokeRefreshToken(ctx context.Context, token string) error {
+	const query = `
+		UPDATE refresh_tokens
+		SET revoked_at = now()
+		WHERE token = $1
+	`
+	tag, err := s.pool.Exec(ctx, query, token)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrRefreshTokenNotFound
+	}
+	return nil
+}
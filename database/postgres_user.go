@@ -0,0 +1,83 @@
+package database
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// postgresUniqueViolation is the pgx error code for a unique constraint
+// violation, used here to detect a duplicate email on insert.
+const postgresUniqueViolation = "23505"
+
+// PostgresUserStore is a UserStore backed by a PostgreSQL connection pool.
+type PostgresUserStore struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresUserStore wraps an existing pgx pool. The caller owns the
+// pool's lifecycle and must close it on shutdown.
+func NewPostgresUserStore(pool *pgxpool.Pool) *PostgresUserStore {
+	return &PostgresUserStore{pool: pool}
+}
+
+func (s *PostgresUserStore) CreateUser(ctx context.Context, email, hashedPassword string) (User, error) {
+	const query = `
+		INSERT INTO users (email, hashed_password)
+		VALUES ($1, $2)
+		RETURNING id, email, hashed_password, created_at
+	`
+	var user User
+	err := s.pool.QueryRow(ctx, query, email, hashedPassword).Scan(
+		&user.ID, &user.Email, &user.HashedPassword, &user.CreatedAt,
+	)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == postgresUniqueViolation {
+		return User{}, ErrEmailTaken
+	}
+	if err != nil {
+		return User{}, err
+	}
+	return user, nil
+}
+
+func (s *PostgresUserStore) GetUserByEmail(ctx context.Context, email string) (User, error) {
+	const query = `
+		SELECT id, email, hashed_password, created_at
+		FROM users
+		WHERE email = $1
+	`
+	var user User
+	err := s.pool.QueryRow(ctx, query, email).Scan(
+		&user.ID, &user.Email, &user.HashedPassword, &user.CreatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return User{}, ErrUserNotFound
+	}
+	if err != nil {
+		return User{}, err
+	}
+	return user, nil
+}
+
+func (s *PostgresUserStore) GetUserByID(ctx context.Context, id int64) (User, error) {
+	const query = `
+		SELECT id, email, hashed_password, created_at
+		FROM users
+		WHERE id = $1
+	`
+	var user User
+	err := s.pool.QueryRow(ctx, query, id).Scan(
+		&user.ID, &user.Email, &user.HashedPassword, &user.CreatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return User{}, ErrUserNotFound
+	}
+	if err != nil {
+		return User{}, err
+	}
+	return user, nil
+}
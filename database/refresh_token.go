@@ -0,0 +1,39 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// RefreshToken tracks a long-lived refresh JWT so it can be looked up and
+// revoked independently of its signature and expiry.
+type RefreshToken struct {
+	Token     string
+	UserID    int64
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	RevokedAt *time.Time
+}
+
+// Revoked reports whether the token has been revoked.
+func (t RefreshToken) Revoked() bool {
+	return t.RevokedAt != nil
+}
+
+// Expired reports whether the token is past its expiry.
+func (t RefreshToken) Expired() bool {
+	return time.Now().UTC().After(t.ExpiresAt)
+}
+
+// ErrRefreshTokenNotFound is returned when a refresh token is not in the
+// store, either because it was never issued or it was pruned.
+var ErrRefreshTokenNotFound = errors.New("refresh token not found")
+
+// RefreshTokenStore persists refresh tokens and their revocation state.
+// Implementations must be safe for concurrent use.
+type RefreshTokenStore interface {
+	CreateRefreshToken(ctx context.Context, token string, userID int64, expiresAt time.Time) error
+	GetRefreshToken(ctx context.Context, token string) (RefreshToken, error)
+	RevokeRefreshToken(ctx context.Context, token string) error
+}
@@ -0,0 +1,31 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// User is a registered Chirpy account.
+type User struct {
+	ID             int64     `json:"id"`
+	Email          string    `json:"email"`
+	HashedPassword string    `json:"-"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// ErrUserNotFound is returned when a user id or email does not exist in the
+// store.
+var ErrUserNotFound = errors.New("user not found")
+
+// ErrEmailTaken is returned by CreateUser when the email is already
+// registered.
+var ErrEmailTaken = errors.New("email is already registered")
+
+// UserStore persists and retrieves user accounts. Implementations must be
+// safe for concurrent use.
+type UserStore interface {
+	CreateUser(ctx context.Context, email, hashedPassword string) (User, error)
+	GetUserByEmail(ctx context.Context, email string) (User, error)
+	GetUserByID(ctx context.Context, id int64) (User, error)
+}
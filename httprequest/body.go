@@ -0,0 +1,58 @@
+// Package httprequest centralizes decoding of JSON request bodies so every
+// handler enforces the same size limit and field strictness.
+package httprequest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// DefaultMaxBytes is the request body size limit used when Body is called
+// with max <= 0.
+const DefaultMaxBytes int64 = 1 << 20 // 1 MiB
+
+// ErrBodyTooLarge is returned by Body when the request body exceeds the
+// configured max size.
+var ErrBodyTooLarge = errors.New("request body too large")
+
+// DecodeError wraps any JSON decoding failure that isn't a size-limit
+// violation: malformed JSON, unknown fields, wrong types, and so on.
+type DecodeError struct {
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("invalid request body: %s", e.Err)
+}
+
+func (e *DecodeError) Unwrap() error { return e.Err }
+
+// Body decodes r's JSON body into v, capping the body at max bytes
+// (DefaultMaxBytes if max <= 0) and rejecting unknown fields. It returns
+// ErrBodyTooLarge if the body exceeded the limit, or a *DecodeError for any
+// other decoding failure, so handlers can map each to the right status
+// code.
+func Body(v any, r *http.Request, max int64) error {
+	if max <= 0 {
+		max = DefaultMaxBytes
+	}
+
+	// MaxBytesReader is documented to use w to close the connection once the
+	// limit is hit; Body's signature has no w to pass, so that part of the
+	// behavior is lost here - only the byte-limit enforcement applies.
+	r.Body = http.MaxBytesReader(nil, r.Body, max)
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(v); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) || err.Error() == "http: request body too large" {
+			return ErrBodyTooLarge
+		}
+		return &DecodeError{Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package httprequest
+
+import (
+	"bytes"
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBodyDecodesValidJSON(t *testing.T) {
+	type payload struct {
+		Name string `json:"name"`
+	}
+
+	req := httptest.NewRequest("POST", "/", bytes.NewBufferString(`{"name": "chirpy"}`))
+	var got payload
+	if err := Body(&got, req, 0); err != nil {
+		t.Fatalf("Body: %s", err)
+	}
+	if got.Name != "chirpy" {
+		t.Errorf("got %+v, want Name=chirpy", got)
+	}
+}
+
+func TestBodyRejectsUnknownFields(t *testing.T) {
+	type payload struct {
+		Name string `json:"name"`
+	}
+
+	req := httptest.NewRequest("POST", "/", bytes.NewBufferString(`{"name": "chirpy", "extra": true}`))
+	var got payload
+	err := Body(&got, req, 0)
+	if err == nil {
+		t.Fatal("expected an error for an unknown field")
+	}
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Errorf("got err %v, want *DecodeError", err)
+	}
+}
+
+func TestBodyTooLarge(t *testing.T) {
+	type payload struct {
+		Name string `json:"name"`
+	}
+
+	req := httptest.NewRequest("POST", "/", bytes.NewBufferString(`{"name": "this body is too long for the limit"}`))
+	var got payload
+	err := Body(&got, req, 10)
+	if !errors.Is(err, ErrBodyTooLarge) {
+		t.Errorf("got err %v, want ErrBodyTooLarge", err)
+	}
+}
+
+func TestBodyMalformedJSON(t *testing.T) {
+	type payload struct {
+		Name string `json:"name"`
+	}
+
+	req := httptest.NewRequest("POST", "/", bytes.NewBufferString(`{not json`))
+	var got payload
+	err := Body(&got, req, 0)
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Errorf("got err %v, want *DecodeError", err)
+	}
+}
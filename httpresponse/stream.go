@@ -0,0 +1,59 @@
+// Package httpresponse holds response-writing helpers shared across
+// handlers, such as the Server-Sent Events stream used by the live chirp
+// feed.
+package httpresponse
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Stream is an open Server-Sent Events connection.
+type Stream struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+}
+
+// TextStream upgrades w to an SSE connection: it sets the standard
+// text/event-stream headers, writes the response header immediately, and
+// flushes so proxies don't buffer the connection. It reports false if w
+// doesn't support flushing.
+func TextStream(w http.ResponseWriter) (*Stream, bool) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return nil, false
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	return &Stream{w: w, flusher: flusher}, true
+}
+
+// Send writes one SSE event with the given event name and data, then
+// flushes it to the client.
+func (s *Stream) Send(event, data string) error {
+	if event != "" {
+		if _, err := fmt.Fprintf(s.w, "event: %s\n", event); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(s.w, "data: %s\n\n", data); err != nil {
+		return err
+	}
+	s.flusher.Flush()
+	return nil
+}
+
+// Heartbeat writes an SSE comment line. Comments are ignored by clients but
+// keep intermediate proxies from closing an otherwise-idle connection.
+func (s *Stream) Heartbeat() error {
+	if _, err := fmt.Fprint(s.w, ": heartbeat\n\n"); err != nil {
+		return err
+	}
+	s.flusher.Flush()
+	return nil
+}
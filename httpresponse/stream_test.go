@@ -0,0 +1,38 @@
+package httpresponse
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTextStreamSetsHeadersAndSendsEvents(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	stream, ok := TextStream(rec)
+	if !ok {
+		t.Fatal("expected TextStream to succeed with an httptest.ResponseRecorder")
+	}
+
+	if got := rec.Header().Get("Content-Type"); got != "text/event-stream" {
+		t.Errorf("Content-Type = %q, want text/event-stream", got)
+	}
+	if got := rec.Header().Get("Cache-Control"); got != "no-cache" {
+		t.Errorf("Cache-Control = %q, want no-cache", got)
+	}
+
+	if err := stream.Send("chirp", `{"id":1}`); err != nil {
+		t.Fatalf("Send: %s", err)
+	}
+	if err := stream.Heartbeat(); err != nil {
+		t.Fatalf("Heartbeat: %s", err)
+	}
+
+	body := rec.Body.String()
+	if !strings.Contains(body, "event: chirp\ndata: {\"id\":1}\n\n") {
+		t.Errorf("body missing chirp event, got %q", body)
+	}
+	if !strings.Contains(body, ": heartbeat\n\n") {
+		t.Errorf("body missing heartbeat comment, got %q", body)
+	}
+}
@@ -1,17 +1,45 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
 	"strings"
-	"sync"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/miketeow/gowebserver/auth"
+	"github.com/miketeow/gowebserver/broker"
+	"github.com/miketeow/gowebserver/config"
+	"github.com/miketeow/gowebserver/database"
+	"github.com/miketeow/gowebserver/httprequest"
+	"github.com/miketeow/gowebserver/httpresponse"
+	"github.com/miketeow/gowebserver/middleware"
+)
+
+const (
+	accessTokenTTL  = time.Hour
+	refreshTokenTTL = 60 * 24 * time.Hour
+
+	chirpStreamHeartbeat = 15 * time.Second
 )
 
 type apiConfig struct {
-	fileserverHits int
-	mu sync.Mutex
+	chirpStore        database.ChirpStore
+	userStore         database.UserStore
+	refreshTokenStore database.RefreshTokenStore
+	chirpBroker       broker.Broker
+	jwtSecret         string
 }
 
 func respondWithError(w http.ResponseWriter, status int, message string) {
@@ -25,6 +53,22 @@ func respondWithJSON(w http.ResponseWriter, status int, payload interface{}) {
 	json.NewEncoder(w).Encode(payload)
 }
 
+// decodeJSONBody decodes r's JSON body into v via httprequest.Body, writing
+// the appropriate error response and returning false if decoding failed.
+func decodeJSONBody(w http.ResponseWriter, r *http.Request, v any) bool {
+	err := httprequest.Body(v, r, 0)
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, httprequest.ErrBodyTooLarge):
+		respondWithError(w, http.StatusRequestEntityTooLarge, "Request body too large")
+	default:
+		log.Printf("Error decoding JSON: %s", err)
+		respondWithError(w, http.StatusBadRequest, "Something went wrong")
+	}
+	return false
+}
+
 func replaceProfaneWords(text string, profaneWords []string) string {
 	words := strings.Fields(text)
     for i, word := range words {
@@ -39,25 +83,12 @@ func replaceProfaneWords(text string, profaneWords []string) string {
     return strings.Join(words, " ")
 }
 
-func (cfg *apiConfig) middlewareMetricsInc(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Increment the counter in a thread-safe manner
-		cfg.mu.Lock()
-		cfg.fileserverHits++
-		cfg.mu.Unlock()
+// metricsHandler renders the admin HTML page, scraping the fileserver hit
+// count from the Prometheus registry rather than keeping its own counter.
+func metricsHandler(w http.ResponseWriter, r *http.Request) {
+	hits := middleware.SumCounter(middleware.FileserverHitsTotal)
 
-		// Call the next handler
-		next.ServeHTTP(w, r)
-	})
-}
-
-func (cfg *apiConfig) metricsHandler(w http.ResponseWriter, r *http.Request) {
-	// Lock the mutex to safely read fileserverHits
-	cfg.mu.Lock()
-	defer cfg.mu.Unlock()
-
-	//Write the number of hits as plain text
-	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 
 	htmlResponse := fmt.Sprintf(`
@@ -67,92 +98,483 @@ func (cfg *apiConfig) metricsHandler(w http.ResponseWriter, r *http.Request) {
 			<p>Chirpy has been visited %d times!</p>
 		</body>
 	</html>
-	`, cfg.fileserverHits)
+	`, int(hits))
 
 	w.Write([]byte(htmlResponse))
 }
 
-func (cfg *apiConfig) resetHandler(w http.ResponseWriter, r *http.Request) {
-	// Lock the mutex to safely reset fileserverHits
-	cfg.mu.Lock()
-	cfg.fileserverHits = 0
-	cfg.mu.Unlock()
+// resetHandler clears the fileserver hit counter, for use in tests.
+func resetHandler(w http.ResponseWriter, r *http.Request) {
+	middleware.FileserverHitsTotal.Reset()
 
-	//Respond with a 200 OK
 	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("Hits counter reset"))
 }
 
-func healthzHandler(w http.ResponseWriter, _ *http.Request) {
-	//Write the content type header
+// livezHandler is a liveness probe: it reports OK as soon as the process
+// is up, regardless of the health of any dependency.
+func livezHandler(w http.ResponseWriter, _ *http.Request) {
 	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
-	//Write the status code 200 OK
 	w.WriteHeader(http.StatusOK)
-	//Write the body text OK
 	w.Write([]byte("OK"))
 }
 
-func (cfg *apiConfig) validateChirpHandler(w http.ResponseWriter, r *http.Request) {
-	// Only allow POST requests
-	if r.Method != http.MethodPost {
-		respondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+// readyzHandler is a readiness probe: it reports 503 until the chirp
+// store and the chirp broker are both reachable.
+func (cfg *apiConfig) readyzHandler(w http.ResponseWriter, r *http.Request) {
+	if !cfg.chirpBroker.Ready() {
+		respondWithError(w, http.StatusServiceUnavailable, "Chirp broker not ready")
 		return
 	}
 
+	if pinger, ok := cfg.chirpStore.(database.Pinger); ok {
+		if err := pinger.Ping(r.Context()); err != nil {
+			log.Printf("Readiness check: chirp store ping failed: %s", err)
+			respondWithError(w, http.StatusServiceUnavailable, "Database not reachable")
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("OK"))
+}
+
+const maxChirpLength = 140
+
+var profaneWords = []string{"kerfuffle", "sharbert", "fornax"}
+
+// chirpsCreateHandler validates and stores a new chirp, authored by the
+// caller authenticated by authMiddleware.
+func (cfg *apiConfig) chirpsCreateHandler(w http.ResponseWriter, r *http.Request) {
 	type chirpRequest struct {
 		Body string `json:"body"`
 	}
 
-	decoder := json.NewDecoder(r.Body)
 	params := &chirpRequest{}
+	if !decodeJSONBody(w, r, params) {
+		return
+	}
+
+	if len(params.Body) > maxChirpLength {
+		respondWithError(w, http.StatusBadRequest, "Chirp is too long")
+		return
+	}
+
+	cleanedBody := replaceProfaneWords(params.Body, profaneWords)
 
-	err := decoder.Decode(params)
+	userID, _ := auth.UserIDFromContext(r.Context())
+	chirp, err := cfg.chirpStore.CreateChirp(r.Context(), cleanedBody, userID)
 	if err != nil {
-		log.Printf("Error decoding JSON: %s", err)
-		respondWithError(w, http.StatusBadRequest, "Something went wrong")
+		log.Printf("Error creating chirp: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Something went wrong")
 		return
 	}
 
-	if len(params.Body) > 140{
-		respondWithError(w, http.StatusBadRequest, "Chirp is too long")
+	cfg.chirpBroker.Publish(chirp)
+
+	respondWithJSON(w, http.StatusCreated, chirp)
+}
+
+// chirpsStreamHandler upgrades the connection to Server-Sent Events and
+// pushes newly-created chirps to the client as they're published.
+func (cfg *apiConfig) chirpsStreamHandler(w http.ResponseWriter, r *http.Request) {
+	stream, ok := httpresponse.TextStream(w)
+	if !ok {
+		respondWithError(w, http.StatusInternalServerError, "Streaming not supported")
+		return
 	}
 
-	profaneWords := []string{"kerfuffle","sharbert","fornax"}
+	chirps, unsubscribe := cfg.chirpBroker.Subscribe()
+	defer unsubscribe()
+
+	heartbeat := time.NewTicker(chirpStreamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case chirp, ok := <-chirps:
+			if !ok {
+				return
+			}
+			payload, err := json.Marshal(chirp)
+			if err != nil {
+				log.Printf("Error marshaling chirp for stream: %s", err)
+				continue
+			}
+			if err := stream.Send("chirp", string(payload)); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if err := stream.Heartbeat(); err != nil {
+				return
+			}
+		}
+	}
+}
 
-	cleanedBody := replaceProfaneWords(params.Body, profaneWords)
+// chirpsListHandler lists chirps, optionally filtered by author and ordered
+// by created_at.
+func (cfg *apiConfig) chirpsListHandler(w http.ResponseWriter, r *http.Request) {
+	params := database.ListChirpsParams{Sort: database.SortAsc}
+
+	if raw := r.URL.Query().Get("author_id"); raw != "" {
+		authorID, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid author_id")
+			return
+		}
+		params.AuthorID = authorID
+	}
+
+	if raw := r.URL.Query().Get("sort"); raw != "" {
+		switch database.SortOrder(raw) {
+		case database.SortAsc, database.SortDesc:
+			params.Sort = database.SortOrder(raw)
+		default:
+			respondWithError(w, http.StatusBadRequest, "Invalid sort")
+			return
+		}
+	}
+
+	chirps, err := cfg.chirpStore.ListChirps(r.Context(), params)
+	if err != nil {
+		log.Printf("Error listing chirps: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Something went wrong")
+		return
+	}
+	if chirps == nil {
+		chirps = []database.Chirp{}
+	}
+
+	respondWithJSON(w, http.StatusOK, chirps)
+}
+
+// chirpsGetHandler returns a single chirp by id.
+func (cfg *apiConfig) chirpsGetHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid chirp id")
+		return
+	}
+
+	chirp, err := cfg.chirpStore.GetChirp(r.Context(), id)
+	if errors.Is(err, database.ErrChirpNotFound) {
+		respondWithError(w, http.StatusNotFound, "Chirp not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Error getting chirp: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Something went wrong")
+		return
+	}
 
-	respondWithJSON(w, http.StatusOK, map[string]string{"cleaned_body": cleanedBody})
+	respondWithJSON(w, http.StatusOK, chirp)
+}
+
+// chirpsDeleteHandler deletes a chirp, but only if the caller authenticated
+// by authMiddleware owns it.
+func (cfg *apiConfig) chirpsDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid chirp id")
+		return
+	}
 
+	userID, _ := auth.UserIDFromContext(r.Context())
+
+	err = cfg.chirpStore.DeleteChirp(r.Context(), id, userID)
+	switch {
+	case errors.Is(err, database.ErrChirpNotFound):
+		respondWithError(w, http.StatusNotFound, "Chirp not found")
+	case errors.Is(err, database.ErrNotChirpOwner):
+		respondWithError(w, http.StatusForbidden, "You do not own this chirp")
+	case err != nil:
+		log.Printf("Error deleting chirp: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Something went wrong")
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// authMiddleware requires a valid "Authorization: Bearer <access token>"
+// header and injects the authenticated user id into the request context.
+func (cfg *apiConfig) authMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenString, err := auth.BearerToken(r)
+		if err != nil {
+			respondWithError(w, http.StatusUnauthorized, "Missing or malformed Authorization header")
+			return
+		}
+
+		userID, err := auth.ValidateAccessJWT(tokenString, cfg.jwtSecret)
+		if err != nil {
+			respondWithError(w, http.StatusUnauthorized, "Invalid or expired access token")
+			return
+		}
+
+		ctx := auth.ContextWithUserID(r.Context(), userID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// usersCreateHandler registers a new user.
+func (cfg *apiConfig) usersCreateHandler(w http.ResponseWriter, r *http.Request) {
+	type userRequest struct {
+		Email    string `json:"email"`
+		Password string `json:"password"`
+	}
+
+	params := &userRequest{}
+	if !decodeJSONBody(w, r, params) {
+		return
+	}
+
+	if params.Email == "" || params.Password == "" {
+		respondWithError(w, http.StatusBadRequest, "Email and password are required")
+		return
+	}
+
+	hashedPassword, err := auth.HashPassword(params.Password)
+	if err != nil {
+		log.Printf("Error hashing password: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Something went wrong")
+		return
+	}
+
+	user, err := cfg.userStore.CreateUser(r.Context(), params.Email, hashedPassword)
+	if errors.Is(err, database.ErrEmailTaken) {
+		respondWithError(w, http.StatusConflict, "Email is already registered")
+		return
+	}
+	if err != nil {
+		log.Printf("Error creating user: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Something went wrong")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, user)
+}
+
+// loginHandler verifies credentials and issues an access JWT and a refresh
+// token.
+func (cfg *apiConfig) loginHandler(w http.ResponseWriter, r *http.Request) {
+	type loginRequest struct {
+		Email    string `json:"email"`
+		Password string `json:"password"`
+	}
+
+	params := &loginRequest{}
+	if !decodeJSONBody(w, r, params) {
+		return
+	}
+
+	user, err := cfg.userStore.GetUserByEmail(r.Context(), params.Email)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Incorrect email or password")
+		return
+	}
+	if err := auth.CheckPasswordHash(params.Password, user.HashedPassword); err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Incorrect email or password")
+		return
+	}
+
+	accessToken, err := auth.MakeAccessJWT(user.ID, cfg.jwtSecret, accessTokenTTL)
+	if err != nil {
+		log.Printf("Error creating access token: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Something went wrong")
+		return
+	}
+
+	refreshToken, err := auth.MakeRefreshJWT(user.ID, cfg.jwtSecret, refreshTokenTTL)
+	if err != nil {
+		log.Printf("Error creating refresh token: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Something went wrong")
+		return
+	}
+
+	expiresAt := time.Now().UTC().Add(refreshTokenTTL)
+	if err := cfg.refreshTokenStore.CreateRefreshToken(r.Context(), refreshToken, user.ID, expiresAt); err != nil {
+		log.Printf("Error storing refresh token: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Something went wrong")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]any{
+		"id":            user.ID,
+		"email":         user.Email,
+		"created_at":    user.CreatedAt,
+		"token":         accessToken,
+		"refresh_token": refreshToken,
+	})
+}
+
+// refreshHandler exchanges a valid, unrevoked refresh token for a new
+// access JWT.
+func (cfg *apiConfig) refreshHandler(w http.ResponseWriter, r *http.Request) {
+	tokenString, err := auth.BearerToken(r)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Missing or malformed Authorization header")
+		return
+	}
+
+	userID, err := auth.ValidateRefreshJWT(tokenString, cfg.jwtSecret)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Invalid or expired refresh token")
+		return
+	}
+
+	stored, err := cfg.refreshTokenStore.GetRefreshToken(r.Context(), tokenString)
+	if errors.Is(err, database.ErrRefreshTokenNotFound) {
+		respondWithError(w, http.StatusUnauthorized, "Refresh token not recognized")
+		return
+	}
+	if err != nil {
+		log.Printf("Error looking up refresh token: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Something went wrong")
+		return
+	}
+	if stored.Revoked() {
+		respondWithError(w, http.StatusUnauthorized, "Refresh token has been revoked")
+		return
+	}
+	if stored.Expired() {
+		respondWithError(w, http.StatusUnauthorized, "Refresh token has expired")
+		return
+	}
+
+	accessToken, err := auth.MakeAccessJWT(userID, cfg.jwtSecret, accessTokenTTL)
+	if err != nil {
+		log.Printf("Error creating access token: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Something went wrong")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]string{"token": accessToken})
+}
+
+// revokeHandler revokes a refresh token so it can no longer be exchanged
+// for access tokens.
+func (cfg *apiConfig) revokeHandler(w http.ResponseWriter, r *http.Request) {
+	tokenString, err := auth.BearerToken(r)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Missing or malformed Authorization header")
+		return
+	}
+
+	err = cfg.refreshTokenStore.RevokeRefreshToken(r.Context(), tokenString)
+	if errors.Is(err, database.ErrRefreshTokenNotFound) {
+		respondWithError(w, http.StatusNotFound, "Refresh token not recognized")
+		return
+	}
+	if err != nil {
+		log.Printf("Error revoking refresh token: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Something went wrong")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
 }
 
 func main() {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Println(err)
+		os.Exit(1)
+	}
+
+	pool, err := pgxpool.New(context.Background(), cfg.DatabaseURL)
+	if err != nil {
+		log.Println("Error connecting to database:", err)
+		os.Exit(1)
+	}
 
-	// Initialize the API configuration
-	apiCfg := &apiConfig{}
+	apiCfg := &apiConfig{
+		chirpStore:        database.NewPostgresChirpStore(pool),
+		userStore:         database.NewPostgresUserStore(pool),
+		refreshTokenStore: database.NewPostgresRefreshTokenStore(pool),
+		chirpBroker:       broker.NewInProcessBroker(0),
+		jwtSecret:         cfg.JWTSecret,
+	}
 
 	mux := http.NewServeMux()
 
-	//Readiness endpoint at /healthz, restrict to GET method only
-	mux.HandleFunc("GET /api/healthz", healthzHandler)
+	// Liveness and readiness endpoints, restricted to GET.
+	mux.HandleFunc("GET /api/livez", livezHandler)
+	mux.HandleFunc("GET /api/readyz", apiCfg.readyzHandler)
 
 	//File server to serve files from the current directory under /app/*
 	fileServer := http.FileServer(http.Dir("."))
-	// Wrap the file server with middleware
-	mux.Handle("/app/", apiCfg.middlewareMetricsInc(http.StripPrefix("/app", fileServer)))
+	// Wrap the file server so its hits are tracked separately, same as before.
+	mux.Handle("/app/", middleware.CountFileserverHits(http.StripPrefix("/app", fileServer)))
+
+	// Admin HTML page rendered from the Prometheus registry, and the
+	// Prometheus text-format endpoint itself.
+	mux.HandleFunc("GET /admin/metrics", metricsHandler)
+	mux.Handle("GET /metrics", promhttp.Handler())
 
-	//Metrics endpoint at /metrics, restrict to GET method only
-	mux.HandleFunc("GET /admin/metrics", apiCfg.metricsHandler)
+	mux.HandleFunc("/api/reset", resetHandler)
 
-	mux.HandleFunc("/api/reset",apiCfg.resetHandler)
+	mux.Handle("POST /api/chirps", apiCfg.authMiddleware(http.HandlerFunc(apiCfg.chirpsCreateHandler)))
+	mux.HandleFunc("GET /api/chirps", apiCfg.chirpsListHandler)
+	mux.HandleFunc("GET /api/chirps/stream", apiCfg.chirpsStreamHandler)
+	mux.HandleFunc("GET /api/chirps/{id}", apiCfg.chirpsGetHandler)
+	mux.Handle("DELETE /api/chirps/{id}", apiCfg.authMiddleware(http.HandlerFunc(apiCfg.chirpsDeleteHandler)))
 
-	mux.HandleFunc("/api/validate_chirp",apiCfg.validateChirpHandler)
+	mux.HandleFunc("POST /api/users", apiCfg.usersCreateHandler)
+	mux.HandleFunc("POST /api/login", apiCfg.loginHandler)
+	mux.HandleFunc("POST /api/refresh", apiCfg.refreshHandler)
+	mux.HandleFunc("POST /api/revoke", apiCfg.revokeHandler)
+
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	handler := middleware.Logger(logger)(middleware.Instrument(mux))
 
 	server := &http.Server{
-		Addr:    ":8080",
-		Handler: mux, // Use the ServeMux as the service handler
+		Addr:              cfg.Addr,
+		Handler:           handler,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
 	}
 
-	fmt.Println("Starting server on port 8080")
-	server.ListenAndServe()
+	serverErrs := make(chan error, 1)
+	go func() {
+		fmt.Println("Starting server on", cfg.Addr)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErrs <- err
+			return
+		}
+		serverErrs <- nil
+	}()
+
+	shutdownSignal := make(chan os.Signal, 1)
+	signal.Notify(shutdownSignal, syscall.SIGINT, syscall.SIGTERM)
+
+	select {
+	case err := <-serverErrs:
+		if err != nil {
+			log.Println("Server error:", err)
+			apiCfg.chirpBroker.Close()
+			pool.Close()
+			os.Exit(1)
+		}
+	case <-shutdownSignal:
+		fmt.Println("Shutting down...")
+
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownGracePeriod)
+		defer cancel()
+
+		if err := server.Shutdown(ctx); err != nil {
+			log.Println("Error during shutdown:", err)
+			apiCfg.chirpBroker.Close()
+			pool.Close()
+			os.Exit(1)
+		}
+		apiCfg.chirpBroker.Close()
+		pool.Close()
+	}
 }
\ No newline at end of file
@@ -0,0 +1,298 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/miketeow/gowebserver/auth"
+	"github.com/miketeow/gowebserver/broker"
+	"github.com/miketeow/gowebserver/database"
+)
+
+func newTestAPIConfig() *apiConfig {
+	return &apiConfig{
+		chirpStore:        database.NewMemoryChirpStore(),
+		userStore:         database.NewMemoryUserStore(),
+		refreshTokenStore: database.NewMemoryRefreshTokenStore(),
+		chirpBroker:       broker.NewInProcessBroker(0),
+		jwtSecret:         "test-secret",
+	}
+}
+
+// withUser returns a copy of req carrying userID in its context, as
+// authMiddleware would after validating an access token.
+func withUser(req *http.Request, userID int64) *http.Request {
+	return req.WithContext(auth.ContextWithUserID(req.Context(), userID))
+}
+
+func mustCreateChirp(t *testing.T, cfg *apiConfig, ctx context.Context, body string, authorID int64) database.Chirp {
+	t.Helper()
+	chirp, err := cfg.chirpStore.CreateChirp(ctx, body, authorID)
+	if err != nil {
+		t.Fatalf("setup: failed to create chirp: %s", err)
+	}
+	return chirp
+}
+
+func TestChirpsCreateHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{
+			name:       "valid chirp",
+			body:       `{"body": "Hello world"}`,
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "chirp too long",
+			body:       `{"body": "` + string(bytes.Repeat([]byte("a"), 141)) + `"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "profane words are cleaned",
+			body:       `{"body": "this is kerfuffle"}`,
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "malformed json",
+			body:       `{not json`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "unknown field rejected",
+			body:       `{"body": "Hello world", "author_id": 99}`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newTestAPIConfig()
+			req := httptest.NewRequest(http.MethodPost, "/api/chirps", bytes.NewBufferString(tt.body))
+			req = withUser(req, 1)
+			rec := httptest.NewRecorder()
+
+			cfg.chirpsCreateHandler(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body: %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+
+			if tt.wantStatus == http.StatusCreated {
+				var chirp database.Chirp
+				if err := json.Unmarshal(rec.Body.Bytes(), &chirp); err != nil {
+					t.Fatalf("failed to decode response: %s", err)
+				}
+				if chirp.ID == 0 {
+					t.Errorf("expected a non-zero chirp id")
+				}
+			}
+		})
+	}
+}
+
+func TestChirpsListHandler(t *testing.T) {
+	cfg := newTestAPIConfig()
+	ctx := context.Background()
+	mustCreateChirp(t, cfg, ctx, "first", 1)
+	mustCreateChirp(t, cfg, ctx, "second", 2)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/chirps?author_id=1", nil)
+	rec := httptest.NewRecorder()
+
+	cfg.chirpsListHandler(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	var chirps []database.Chirp
+	if err := json.Unmarshal(rec.Body.Bytes(), &chirps); err != nil {
+		t.Fatalf("failed to decode response: %s", err)
+	}
+	if len(chirps) != 1 || chirps[0].AuthorID != 1 {
+		t.Errorf("got %+v, want a single chirp from author 1", chirps)
+	}
+}
+
+func TestChirpsStreamHandlerPushesPublishedChirps(t *testing.T) {
+	cfg := newTestAPIConfig()
+
+	srv := httptest.NewServer(http.HandlerFunc(cfg.chirpsStreamHandler))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("GET stream: %s", err)
+	}
+	defer resp.Body.Close()
+
+	// Give the handler a moment to subscribe before publishing.
+	time.Sleep(20 * time.Millisecond)
+
+	chirp := database.Chirp{ID: 1, Body: "hello"}
+	cfg.chirpBroker.Publish(chirp)
+
+	lines := make(chan string)
+	go func() {
+		reader := bufio.NewReader(resp.Body)
+		for {
+			line, err := reader.ReadString('\n')
+			if line != "" {
+				lines <- line
+			}
+			if err != nil {
+				close(lines)
+				return
+			}
+		}
+	}()
+
+	var gotEvent, gotData bool
+	timeout := time.After(2 * time.Second)
+	for !gotEvent || !gotData {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				t.Fatalf("stream closed before observing expected SSE event (gotEvent=%v, gotData=%v)", gotEvent, gotData)
+			}
+			if strings.HasPrefix(line, "event: chirp") {
+				gotEvent = true
+			}
+			if strings.Contains(line, `"body":"hello"`) {
+				gotData = true
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for expected SSE event (gotEvent=%v, gotData=%v)", gotEvent, gotData)
+		}
+	}
+}
+
+func TestReadyzHandler(t *testing.T) {
+	cfg := newTestAPIConfig()
+	req := httptest.NewRequest(http.MethodGet, "/api/readyz", nil)
+	rec := httptest.NewRecorder()
+
+	cfg.readyzHandler(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d (body: %s)", rec.Code, http.StatusOK, rec.Body.String())
+	}
+
+	cfg.chirpBroker.Close()
+	rec = httptest.NewRecorder()
+	cfg.readyzHandler(rec, req)
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want %d once the broker is closed", rec.Code, http.StatusServiceUnavailable)
+	}
+}
+
+// pingFailingChirpStore wraps a ChirpStore so its database.Pinger check
+// always fails, simulating a database that's down or unreachable - the
+// same failure readyzHandler must catch against a real PostgresChirpStore.
+type pingFailingChirpStore struct {
+	database.ChirpStore
+}
+
+func (pingFailingChirpStore) Ping(_ context.Context) error {
+	return errors.New("database unreachable")
+}
+
+func TestReadyzHandlerReportsDatabaseDown(t *testing.T) {
+	cfg := newTestAPIConfig()
+	cfg.chirpStore = pingFailingChirpStore{ChirpStore: cfg.chirpStore}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/readyz", nil)
+	rec := httptest.NewRecorder()
+
+	cfg.readyzHandler(rec, req)
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want %d when the chirp store can't be pinged", rec.Code, http.StatusServiceUnavailable)
+	}
+}
+
+func TestChirpsGetHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		idPath     string
+		wantStatus int
+	}{
+		{name: "existing chirp", wantStatus: http.StatusOK},
+		{name: "chirp not found", idPath: "99999", wantStatus: http.StatusNotFound},
+		{name: "invalid chirp id", idPath: "not-a-number", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newTestAPIConfig()
+			chirp := mustCreateChirp(t, cfg, context.Background(), "hi", 1)
+
+			idStr := tt.idPath
+			if idStr == "" {
+				idStr = strconv.FormatInt(chirp.ID, 10)
+			}
+			req := httptest.NewRequest(http.MethodGet, "/api/chirps/"+idStr, nil)
+			req.SetPathValue("id", idStr)
+			rec := httptest.NewRecorder()
+
+			cfg.chirpsGetHandler(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body: %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+
+			if tt.wantStatus == http.StatusOK {
+				var got database.Chirp
+				if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+					t.Fatalf("failed to decode response: %s", err)
+				}
+				if got.ID != chirp.ID {
+					t.Errorf("got chirp id %d, want %d", got.ID, chirp.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestChirpsDeleteHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		callerID   int64
+		wantStatus int
+	}{
+		{name: "owner can delete", callerID: 1, wantStatus: http.StatusNoContent},
+		{name: "non-owner forbidden", callerID: 2, wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newTestAPIConfig()
+			chirp := mustCreateChirp(t, cfg, context.Background(), "hi", 1)
+
+			idStr := strconv.FormatInt(chirp.ID, 10)
+			req := httptest.NewRequest(http.MethodDelete, "/api/chirps/"+idStr, nil)
+			req.SetPathValue("id", idStr)
+			req = withUser(req, tt.callerID)
+			rec := httptest.NewRecorder()
+
+			cfg.chirpsDeleteHandler(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body: %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+		})
+	}
+}
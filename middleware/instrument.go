@@ -0,0 +1,136 @@
+// Package middleware provides HTTP middleware for observability: Prometheus
+// metrics via Instrument and structured request logging via Logger.
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// HTTPRequestsTotal counts every request Instrument sees, labeled by
+	// method, route pattern, and response status. The route pattern (e.g.
+	// "GET /api/chirps/{id}"), not the raw request path, keeps the label
+	// bounded regardless of how many distinct ids get requested.
+	HTTPRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Total number of HTTP requests.",
+	}, []string{"method", "pattern", "status"})
+
+	// HTTPRequestDuration records request latency in seconds, labeled the
+	// same way as HTTPRequestsTotal.
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request duration in seconds.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "pattern", "status"})
+
+	// FileserverHitsTotal counts requests served by the static file
+	// server, labeled by path. It predates Instrument and is kept as its
+	// own metric so the admin page can keep rendering it.
+	FileserverHitsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "fileserver_hits_total",
+		Help: "Total number of requests served by the file server.",
+	}, []string{"path"})
+)
+
+// statusRecorder wraps http.ResponseWriter to capture the status code and
+// bytes written, since the standard library gives handlers no way to read
+// either back after the fact.
+type statusRecorder struct {
+	http.ResponseWriter
+	status       int
+	bytesWritten int
+	wroteHeader  bool
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.wroteHeader {
+		return
+	}
+	r.status = status
+	r.wroteHeader = true
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+	n, err := r.ResponseWriter.Write(b)
+	r.bytesWritten += n
+	return n, err
+}
+
+// Flush implements http.Flusher by delegating to the underlying
+// ResponseWriter. Without it, wrapping a handler in statusRecorder would
+// silently break streaming responses (e.g. Server-Sent Events), since
+// embedding the http.ResponseWriter interface doesn't promote Flush.
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Instrument wraps mux, recording HTTPRequestsTotal and
+// HTTPRequestDuration for every request. It labels each observation with
+// mux's registered route pattern rather than the raw request path, since
+// path-valued routes like "GET /api/chirps/{id}" would otherwise create a
+// new time series per id requested.
+func Instrument(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, pattern := mux.Handler(r)
+		if pattern == "" {
+			pattern = "unmatched"
+		}
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+
+		// mux.ServeHTTP, not the handler mux.Handler returned: only ServeHTTP
+		// populates the request's path-value matches, which handlers read via
+		// r.PathValue. Calling the handler directly is cheaper but silently
+		// empties every {id}-style path value.
+		mux.ServeHTTP(rec, r)
+
+		status := strconv.Itoa(rec.status)
+		duration := time.Since(start).Seconds()
+
+		HTTPRequestsTotal.WithLabelValues(r.Method, pattern, status).Inc()
+		HTTPRequestDuration.WithLabelValues(r.Method, pattern, status).Observe(duration)
+	})
+}
+
+// CountFileserverHits increments FileserverHitsTotal for each request
+// served through next.
+func CountFileserverHits(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		FileserverHitsTotal.WithLabelValues(r.URL.Path).Inc()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// SumCounter adds up every labeled value of a CounterVec. It's used by the
+// admin HTML page to render a total scraped from the registry instead of
+// keeping a separate ad-hoc counter.
+func SumCounter(cv *prometheus.CounterVec) float64 {
+	ch := make(chan prometheus.Metric, 16)
+	cv.Collect(ch)
+	close(ch)
+
+	var total float64
+	for m := range ch {
+		var pb dto.Metric
+		if err := m.Write(&pb); err != nil {
+			continue
+		}
+		total += pb.GetCounter().GetValue()
+	}
+	return total
+}
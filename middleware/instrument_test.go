@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// countDistinctSeries counts how many distinct label combinations a
+// CounterVec has recorded.
+func countDistinctSeries(cv *prometheus.CounterVec) int {
+	ch := make(chan prometheus.Metric, 64)
+	cv.Collect(ch)
+	close(ch)
+
+	n := 0
+	for range ch {
+		n++
+	}
+	return n
+}
+
+func TestInstrumentRecordsStatus(t *testing.T) {
+	HTTPRequestsTotal.Reset()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /brew", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	handler := Instrument(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/brew", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusTeapot {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusTeapot)
+	}
+
+	got := SumCounter(HTTPRequestsTotal)
+	if got != 1 {
+		t.Errorf("got %v requests recorded, want 1", got)
+	}
+}
+
+func TestInstrumentLabelsByRoutePatternNotPath(t *testing.T) {
+	HTTPRequestsTotal.Reset()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/chirps/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := Instrument(mux)
+
+	for _, id := range []string{"1", "2", "3"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/chirps/"+id, nil)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+	}
+
+	// All three requests hit the same route pattern, so they must collapse
+	// into a single time series rather than one per id.
+	if got := SumCounter(HTTPRequestsTotal); got != 3 {
+		t.Errorf("got %v requests under the route pattern label, want 3", got)
+	}
+
+	if got := countDistinctSeries(HTTPRequestsTotal); got != 1 {
+		t.Errorf("got %d distinct time series, want 1 (one per id would indicate unbounded cardinality)", got)
+	}
+}
+
+func TestInstrumentPreservesPathValue(t *testing.T) {
+	var gotID string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/chirps/{id}", func(w http.ResponseWriter, r *http.Request) {
+		gotID = r.PathValue("id")
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := Instrument(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/chirps/42", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	// mux.Handler(r) alone doesn't populate r.PathValue - only mux.ServeHTTP
+	// does. Calling the handler mux.Handler returns instead of dispatching
+	// through the mux would pass this request through with an empty id.
+	if gotID != "42" {
+		t.Fatalf("r.PathValue(%q) = %q, want %q", "id", gotID, "42")
+	}
+}
+
+func TestInstrumentPreservesFlusher(t *testing.T) {
+	var flushed bool
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /stream", func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			t.Fatal("expected the wrapped ResponseWriter to implement http.Flusher")
+		}
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+		flushed = true
+	})
+	handler := Instrument(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/stream", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if !flushed {
+		t.Fatal("handler never reached the flush")
+	}
+	if !rec.Flushed {
+		t.Error("expected the underlying recorder to observe a flush")
+	}
+}
+
+func TestCountFileserverHits(t *testing.T) {
+	FileserverHitsTotal.Reset()
+
+	handler := CountFileserverHits(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/app/index.html", nil)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+	}
+
+	if got := SumCounter(FileserverHitsTotal); got != 3 {
+		t.Errorf("got %v fileserver hits, want 3", got)
+	}
+}